@@ -0,0 +1,431 @@
+package md
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal parses the Markdown encoding produced by Marshal and
+// stores the result in the value pointed to by v, symmetric to
+// encoding/json.Unmarshal and encoding/xml.Unmarshal.
+//
+// v must be a non-nil pointer to a struct, slice, array, or map,
+// matching the shape Marshal produced. Indentation depth in data is
+// used to reconstruct nesting into struct fields, slices, and maps,
+// honoring the same markdown struct tag names Marshal uses. Pointers
+// encountered along the way are allocated as needed, and "link"
+// tagged fields are parsed back from "<url|label>" into a [2]string
+// or a URL/Text struct.
+//
+// Fields marshaled with the "obfuscate" tag option round-trip as
+// whatever opaque string Marshal left behind; the original value
+// cannot be recovered, since obfuscation is lossy by design. Values
+// wrapped in a multi-line fenced code block (the "code" tag option,
+// for multi-line content) are not supported, since this parser is
+// line-oriented; single-line inline code is unwrapped normally.
+func Unmarshal(data []byte, v interface{}) error {
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalMarkdown(data)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("md: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+
+	nodes, err := parseNodes(data)
+	if err != nil {
+		return err
+	}
+
+	target := rv.Elem()
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		target = target.Elem()
+	}
+
+	switch target.Kind() {
+	case reflect.Struct:
+		return unmarshalStruct(nodes, target)
+	case reflect.Slice:
+		return unmarshalSlice(nodes, target)
+	case reflect.Array:
+		return unmarshalArray(nodes, target)
+	case reflect.Map:
+		return unmarshalMap(nodes, target)
+	default:
+		return fmt.Errorf("md: cannot unmarshal into %s", target.Type())
+	}
+}
+
+// node is one parsed bullet line, plus whatever deeper-indented
+// bullets belong to it.
+type node struct {
+	hasKey   bool
+	key      string
+	value    string
+	children []*node
+}
+
+// parseNodes tokenizes the indented "- **Field**: value" bullets in
+// data into a forest of nodes, using leading tabs to determine
+// nesting depth.
+func parseNodes(data []byte) ([]*node, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var roots []*node
+	var stack []*node // stack[d] is the most recent node seen at depth d
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		depth, n, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if depth == 0 {
+			roots = append(roots, n)
+		} else {
+			if depth > len(stack) {
+				return nil, fmt.Errorf("md: unexpected indentation in line %q", line)
+			}
+
+			parent := stack[depth-1]
+			parent.children = append(parent.children, n)
+		}
+
+		if depth < len(stack) {
+			stack = stack[:depth]
+		}
+		stack = append(stack, n)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return roots, nil
+}
+
+// parseLine parses a single line into its indentation depth and
+// node. Lines look like "- **Field**: value" or "- value" for list
+// items.
+func parseLine(line string) (int, *node, error) {
+	depth := 0
+	for strings.HasPrefix(line, "\t") {
+		line = line[1:]
+		depth++
+	}
+
+	if !strings.HasPrefix(line, "- ") {
+		return 0, nil, fmt.Errorf("md: malformed line %q", line)
+	}
+
+	content := line[len("- "):]
+
+	if strings.HasPrefix(content, "**") {
+		if idx := strings.Index(content, "**: "); idx >= 0 {
+			return depth, &node{
+				hasKey: true,
+				key:    content[2:idx],
+				value:  content[idx+len("**: "):],
+			}, nil
+		}
+	}
+
+	return depth, &node{value: content}, nil
+}
+
+// fieldByTagName maps the effective name of every encodable field
+// of t (its markdown tag name, or its Go name) to its index.
+func fieldByTagName(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		fieldTagOpts := parseTag(fieldType.Tag.Get(tag))
+		if fieldTagOpts.omit {
+			continue
+		}
+
+		name := fieldType.Name
+		if fieldTagOpts.name != "" {
+			name = fieldTagOpts.name
+		}
+
+		fields[name] = i
+	}
+
+	return fields
+}
+
+func unmarshalStruct(nodes []*node, target reflect.Value) error {
+	fields := fieldByTagName(target.Type())
+
+	for _, n := range nodes {
+		if !n.hasKey {
+			return fmt.Errorf("md: expected a \"- **Field**: value\" bullet, got %q", n.value)
+		}
+
+		idx, ok := fields[n.key]
+		if !ok {
+			continue // unknown field: ignore, like encoding/json does
+		}
+
+		if err := unmarshalNode(n, target.Field(idx)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmarshalNode assigns the value described by n into target,
+// allocating pointers and recursing into structs, slices, and maps
+// as needed.
+func unmarshalNode(n *node, target reflect.Value) error {
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		if u, ok := target.Interface().(Unmarshaler); ok {
+			return u.UnmarshalMarkdown(renderNode(n))
+		}
+
+		target = target.Elem()
+	}
+
+	if target.CanAddr() {
+		if u, ok := target.Addr().Interface().(Unmarshaler); ok {
+			return u.UnmarshalMarkdown(renderNode(n))
+		}
+	}
+
+	if target.Kind() == reflect.Struct || target.Kind() == reflect.Array {
+		if ok := unmarshalLink(n, target); ok {
+			return nil
+		}
+	}
+
+	switch target.Kind() {
+	case reflect.Struct:
+		return unmarshalStruct(n.children, target)
+	case reflect.Slice:
+		return unmarshalSlice(n.children, target)
+	case reflect.Array:
+		return unmarshalArray(n.children, target)
+	case reflect.Map:
+		return unmarshalMap(n.children, target)
+	default:
+		return unmarshalScalar(stripInlineCode(n.value), target)
+	}
+}
+
+// unmarshalLink reverses marshalLink: it recognizes a flat
+// "<url|label>" bullet value and, if target is link-shaped (a
+// [2]string or a struct with URL and Text string fields), assigns
+// the URL and label into it. It reports whether it did so; when it
+// returns false, target is untouched and the caller should fall
+// back to its normal struct/array handling.
+func unmarshalLink(n *node, target reflect.Value) bool {
+	if len(n.children) != 0 {
+		return false
+	}
+
+	url, label, ok := parseLinkValue(stripInlineCode(n.value))
+	if !ok {
+		return false
+	}
+
+	switch {
+	case target.Kind() == reflect.Array &&
+		target.Len() == 2 &&
+		target.Type().Elem().Kind() == reflect.String:
+		target.Index(0).SetString(url)
+		target.Index(1).SetString(label)
+
+		return true
+
+	case target.Kind() == reflect.Struct:
+		urlField := target.FieldByName("URL")
+		textField := target.FieldByName("Text")
+
+		if urlField.IsValid() && urlField.CanSet() && urlField.Kind() == reflect.String &&
+			textField.IsValid() && textField.CanSet() && textField.Kind() == reflect.String {
+			urlField.SetString(url)
+			textField.SetString(label)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseLinkValue parses the "<url|label>" syntax marshalLink
+// renders link-tagged fields as.
+func parseLinkValue(s string) (url, label string, ok bool) {
+	if len(s) < 2 || !strings.HasPrefix(s, "<") || !strings.HasSuffix(s, ">") {
+		return "", "", false
+	}
+
+	inner := s[1 : len(s)-1]
+
+	idx := strings.Index(inner, "|")
+	if idx < 0 {
+		return "", "", false
+	}
+
+	return inner[:idx], inner[idx+1:], true
+}
+
+func unmarshalSlice(children []*node, target reflect.Value) error {
+	slice := reflect.MakeSlice(target.Type(), 0, len(children))
+
+	for _, c := range children {
+		elem := reflect.New(target.Type().Elem()).Elem()
+		if err := unmarshalNode(c, elem); err != nil {
+			return err
+		}
+
+		slice = reflect.Append(slice, elem)
+	}
+
+	target.Set(slice)
+
+	return nil
+}
+
+func unmarshalArray(children []*node, target reflect.Value) error {
+	for i := 0; i < target.Len() && i < len(children); i++ {
+		if err := unmarshalNode(children[i], target.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unmarshalMap(children []*node, target reflect.Value) error {
+	keyType := target.Type().Key()
+	if keyType.Kind() != reflect.String {
+		return fmt.Errorf("md: unmarshal only supports string-keyed maps, got %s", keyType)
+	}
+
+	m := reflect.MakeMapWithSize(target.Type(), len(children))
+
+	for _, c := range children {
+		if !c.hasKey {
+			return fmt.Errorf("md: expected a \"- **key**: value\" bullet in map, got %q", c.value)
+		}
+
+		elem := reflect.New(target.Type().Elem()).Elem()
+		if err := unmarshalNode(c, elem); err != nil {
+			return err
+		}
+
+		key := reflect.New(keyType).Elem()
+		key.SetString(c.key)
+
+		m.SetMapIndex(key, elem)
+	}
+
+	target.Set(m)
+
+	return nil
+}
+
+func unmarshalScalar(s string, target reflect.Value) error {
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		target.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(f)
+	default:
+		return fmt.Errorf("md: cannot unmarshal %q into %s", s, target.Type())
+	}
+
+	return nil
+}
+
+// stripInlineCode undoes the inline backticks the "code" tag option
+// wraps single-line values in.
+func stripInlineCode(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, "`") && strings.HasSuffix(s, "`") {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
+
+// renderNode reconstructs the raw Markdown text a node's subtree was
+// parsed from, for handing to an Unmarshaler implementation.
+func renderNode(n *node) []byte {
+	if len(n.children) == 0 {
+		return []byte(n.value)
+	}
+
+	var buf bytes.Buffer
+
+	renderChildren(&buf, n.children, 0)
+
+	return buf.Bytes()
+}
+
+func renderChildren(buf *bytes.Buffer, children []*node, depth int) {
+	for i, c := range children {
+		if i > 0 || depth > 0 {
+			buf.WriteByte('\n')
+		}
+
+		buf.WriteString(strings.Repeat("\t", depth))
+
+		if c.hasKey {
+			fmt.Fprintf(buf, "- **%s**: %s", c.key, c.value)
+		} else {
+			fmt.Fprintf(buf, "- %s", c.value)
+		}
+
+		if len(c.children) > 0 {
+			renderChildren(buf, c.children, depth+1)
+		}
+	}
+}
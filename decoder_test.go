@@ -0,0 +1,141 @@
+package md
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalStructRoundTrip(t *testing.T) {
+	type inner struct {
+		Value int
+	}
+
+	type outer struct {
+		Name   string
+		Tags   []string
+		Nested inner
+	}
+
+	in := outer{Name: "widget", Tags: []string{"a", "b"}, Nested: inner{Value: 7}}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out outer
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+}
+
+func TestUnmarshalNonStructRoot(t *testing.T) {
+	data, err := Marshal([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []string
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal into *[]string should not fail, got %v", err)
+	}
+
+	want := []string{"a", "b"}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("got %+v, want %+v", out, want)
+	}
+}
+
+func TestUnmarshalMapRoot(t *testing.T) {
+	data, err := Marshal(map[string]int{"a": 1, "b": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out map[string]int
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"a": 1, "b": 2}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("got %+v, want %+v", out, want)
+	}
+}
+
+func TestUnmarshalArrayRoot(t *testing.T) {
+	data, err := Marshal([2]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out [2]int
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [2]int{1, 2}
+	if out != want {
+		t.Errorf("got %+v, want %+v", out, want)
+	}
+}
+
+func TestUnmarshalScalarRootFails(t *testing.T) {
+	var out string
+	if err := Unmarshal([]byte("- value"), &out); err == nil {
+		t.Error("expected an error unmarshaling into a non-struct, non-collection root")
+	}
+}
+
+func TestUnmarshalLinkArray(t *testing.T) {
+	type s struct {
+		Link [2]string `markdown:",link"`
+	}
+
+	in := s{Link: [2]string{"http://example.com", "Example"}}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out s
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+}
+
+func TestUnmarshalLinkStruct(t *testing.T) {
+	type link struct {
+		URL  string
+		Text string
+	}
+
+	type s struct {
+		Ref link `markdown:",link"`
+	}
+
+	in := s{Ref: link{URL: "http://example.com", Text: "Example"}}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out s
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+}
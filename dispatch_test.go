@@ -0,0 +1,87 @@
+package md
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type upperCaser string
+
+func (u upperCaser) String() string { return "STR:" + string(u) }
+
+type csvPair struct{ A, B string }
+
+func (c csvPair) MarshalText() ([]byte, error) {
+	return []byte(c.A + "," + c.B), nil
+}
+
+type contextGreeter struct{ Name string }
+
+func (g contextGreeter) MarshalMarkdownContext(ctx context.Context) ([]byte, error) {
+	if greeting, ok := ctx.Value(greetingKey{}).(string); ok {
+		return []byte(greeting + ", " + g.Name), nil
+	}
+
+	return []byte("hi, " + g.Name), nil
+}
+
+type greetingKey struct{}
+
+func TestMarshalStringer(t *testing.T) {
+	got, err := Marshal(upperCaser("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "STR:x" {
+		t.Errorf("got %q, want %q", got, "STR:x")
+	}
+}
+
+func TestMarshalTextMarshaler(t *testing.T) {
+	got, err := Marshal(csvPair{A: "a", B: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "a,b" {
+		t.Errorf("got %q, want %q", got, "a,b")
+	}
+}
+
+func TestMarshalTimeDefaultLayout(t *testing.T) {
+	tm := time.Date(2024, 3, 5, 1, 2, 3, 0, time.UTC)
+
+	got, err := Marshal(tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "2024-03-05T01:02:03Z"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalContextThreadsToMarshalerContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), greetingKey{}, "hello")
+
+	got, err := MarshalContext(ctx, contextGreeter{Name: "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "hello, world" {
+		t.Errorf("got %q, want %q", got, "hello, world")
+	}
+
+	got, err = Marshal(contextGreeter{Name: "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "hi, world" {
+		t.Errorf("got %q, want %q", got, "hi, world")
+	}
+}
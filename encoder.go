@@ -0,0 +1,165 @@
+package md
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// Dialect selects the flavor of Markdown an Encoder produces.
+type Dialect int
+
+const (
+	// DialectCommonMark renders "**bold**" field names and "-"
+	// bullets. This is the default, and matches the output of
+	// this package prior to the introduction of Encoder.
+	DialectCommonMark Dialect = iota
+
+	// DialectSlackMrkdwn renders "*bold*" field names and "•"
+	// bullets, matching Slack's mrkdwn flavor.
+	DialectSlackMrkdwn
+
+	// DialectSlackBlockKit renders the same text as
+	// DialectSlackMrkdwn, but wraps it as the "text" of a single
+	// Slack Block Kit "section" block, and Encode writes a JSON
+	// array of blocks instead of raw Markdown.
+	DialectSlackBlockKit
+)
+
+// Encoder writes the Markdown encoding of values to an output
+// stream, modeled on xml.Encoder and json.Encoder.
+type Encoder struct {
+	w            *bufio.Writer
+	dialect      Dialect
+	prefix       string
+	indent       string
+	indentWasSet bool
+	timeLayout   string
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: bufio.NewWriter(w),
+	}
+}
+
+// Indent sets the indentation to use when Encode is called: each
+// indentation level consists of prefix followed by one or more
+// copies of indent, matching json.Encoder.SetIndent. Passing two
+// empty strings disables indentation. The default, when Indent is
+// never called, is a single tab per level.
+func (e *Encoder) Indent(prefix, indent string) {
+	e.prefix = prefix
+	e.indent = indent
+	e.indentWasSet = true
+}
+
+// SetDialect selects the Markdown flavor Encode produces. The
+// default, when SetDialect is never called, is DialectCommonMark.
+func (e *Encoder) SetDialect(dialect Dialect) {
+	e.dialect = dialect
+}
+
+// SetTimeLayout sets the layout used to format time.Time values.
+// The default, when SetTimeLayout is never called, is time.RFC3339.
+func (e *Encoder) SetTimeLayout(layout string) {
+	e.timeLayout = layout
+}
+
+// Encode writes the Markdown encoding of v to the stream.
+func (e *Encoder) Encode(v interface{}) error {
+	return e.EncodeContext(context.Background(), v)
+}
+
+// EncodeContext is like Encode, but threads ctx down to any
+// MarshalerContext implementations encountered along the way.
+func (e *Encoder) EncodeContext(ctx context.Context, v interface{}) error {
+	if e.dialect == DialectSlackBlockKit {
+		return e.encodeBlockKit(ctx, v)
+	}
+
+	opts := e.rootOpts(e.dialect)
+	opts.ctx = ctx
+
+	if err := marshal(e.w, v, opts); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// blockKitSection is a single Slack Block Kit "section" block.
+type blockKitSection struct {
+	Type string       `json:"type"`
+	Text blockKitText `json:"text"`
+}
+
+// blockKitText is the "text" object of a Block Kit section block.
+type blockKitText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (e *Encoder) encodeBlockKit(ctx context.Context, v interface{}) error {
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+
+	opts := e.rootOpts(DialectSlackMrkdwn)
+	opts.ctx = ctx
+
+	if err := marshal(bw, v, opts); err != nil {
+		return err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	blocks := []blockKitSection{{
+		Type: "section",
+		Text: blockKitText{Type: "mrkdwn", Text: buf.String()},
+	}}
+
+	encoded, err := json.Marshal(blocks)
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(encoded); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// rootOpts builds the marshalOpts for a top-level Encode call,
+// rendering in the given dialect regardless of e.dialect.
+func (e *Encoder) rootOpts(dialect Dialect) marshalOpts {
+	indentUnit := e.indent
+	if !e.indentWasSet {
+		indentUnit = "\t"
+	}
+
+	boldFormat, bullet := dialectStyle(dialect)
+
+	return marshalOpts{
+		prefix:     e.prefix,
+		indentUnit: indentUnit,
+		boldFormat: boldFormat,
+		bullet:     bullet,
+		timeLayout: e.timeLayout,
+	}
+}
+
+// dialectStyle returns the bold-text format verb and list bullet a
+// dialect renders with.
+func dialectStyle(dialect Dialect) (boldFormat, bullet string) {
+	if dialect == DialectSlackMrkdwn {
+		return "*%s*", "•"
+	}
+
+	return "**%s**", "-"
+}
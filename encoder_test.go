@@ -0,0 +1,119 @@
+package md
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type encoderSample struct {
+	Name string
+	Tags []string
+}
+
+func TestEncoderDefaultIndent(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(encoderSample{Name: "x", Tags: []string{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Name**: x\n- **Tags**: \n\t- a\n\t- b"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoderIndentCustom(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(encoderSample{Name: "x", Tags: []string{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Name**: x\n- **Tags**: \n  - a\n  - b"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoderIndentDisabled(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.Indent("", "")
+
+	if err := enc.Encode(encoderSample{Name: "x", Tags: []string{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Name**: x\n- **Tags**: \n- a\n- b"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoderSlackMrkdwnDialect(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.SetDialect(DialectSlackMrkdwn)
+
+	if err := enc.Encode(encoderSample{Name: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "• *Name*: x\n• *Tags*: null"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoderSlackBlockKitDialect(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.SetDialect(DialectSlackBlockKit)
+
+	if err := enc.Encode(encoderSample{Name: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var blocks []blockKitSection
+	if err := json.Unmarshal(buf.Bytes(), &blocks); err != nil {
+		t.Fatalf("output is not a JSON array of blocks: %v", err)
+	}
+
+	if len(blocks) != 1 {
+		t.Fatalf("got %d blocks, want 1", len(blocks))
+	}
+
+	want := blockKitSection{
+		Type: "section",
+		Text: blockKitText{Type: "mrkdwn", Text: "• *Name*: x\n• *Tags*: null"},
+	}
+	if blocks[0] != want {
+		t.Errorf("got %+v, want %+v", blocks[0], want)
+	}
+}
+
+func TestEncoderTimeLayout(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.SetTimeLayout("2006-01-02")
+
+	if err := enc.Encode(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "2024-03-05"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -1,9 +1,15 @@
 package md
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 )
 
 const (
@@ -11,10 +17,18 @@ const (
 
 	tagOmitField      = "-"
 	tagObfuscateField = "obfuscate"
+
+	tagOmitEmptyOption = "omitempty"
+	tagCodeOption      = "code"
+	tagLinkOption      = "link"
 )
 
 var (
-	marshalerType = reflect.TypeOf(new(Marshaler)).Elem()
+	marshalerType        = reflect.TypeOf(new(Marshaler)).Elem()
+	marshalerContextType = reflect.TypeOf(new(MarshalerContext)).Elem()
+	textMarshalerType    = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
+	stringerType         = reflect.TypeOf(new(fmt.Stringer)).Elem()
+	timeType             = reflect.TypeOf(time.Time{})
 )
 
 // Marshal returns the Slack Markdown encoding of v.
@@ -27,27 +41,77 @@ var (
 // which will be automatically dereferenced, and for objects
 // implementing the Marshaler interface.
 //
-// Two tags are provided to facilitate proper serilializing.
+// Slices and arrays are rendered as an indented bulleted list
+// of their recursively marshaled elements. Maps are rendered
+// the same way, with each entry as a bold key followed by its
+// value, sorted by the string form of the key. Nil slices and
+// maps render as "null", like nil pointers; empty ones render
+// as an empty string.
+//
+// The markdown struct tag takes a comma-separated list of
+// options, in the style of encoding/json:
 //
 //   // Field is ignored by this package.
-//   Field int `json:"-"`
+//   Field int `markdown:"-"`
+//
+//   // Field is rendered as "DisplayName" instead of "Field".
+//   Field int `markdown:"DisplayName"`
+//
+//   // Field is omitted if it holds its zero value.
+//   Field int `markdown:"DisplayName,omitempty"`
+//
+//   // Field appears, but at most the last 4 characters are shown.
+//   // Only string values are obfuscated.
+//   Field string `markdown:",obfuscate"`
 //
-//   // Field appears, but at most the last 4 characters are shown
-//   Field int `json:"obfuscate"`
+//   // Field is wrapped in backticks (or a fenced block, if multi-line).
+//   Field int `markdown:",code"`
 //
-// Those tags will preventing sensitive that from showing in your
-// Slack channels.
+//   // Field is rendered as a Slack link, "<url|label>". This requires
+//   // Field to be a [2]string{url, label} or a struct with URL and
+//   // Text string fields.
+//   Field LinkField `markdown:",link"`
 //
+// Options may be combined, e.g. `markdown:"DisplayName,omitempty,code"`.
+//
+// Marshal is a thin wrapper around Encoder, provided for callers that
+// just want a []byte instead of streaming to an io.Writer.
 func Marshal(v interface{}) ([]byte, error) {
-	return marshal(
-		v,
-		marshalOpts{indentLevel: 0},
-	)
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
 }
 
+// MarshalContext is like Marshal, but threads ctx down to any
+// MarshalerContext implementations encountered along the way.
+func MarshalContext(ctx context.Context, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf).EncodeContext(ctx, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// marshalOpts carries the state threaded through a single marshal
+// call tree: how deep we are, whether we're obfuscating the current
+// field, and the formatting the active Encoder dialect calls for.
 type marshalOpts struct {
 	indentLevel int
 	obfuscate   bool
+
+	prefix     string
+	indentUnit string
+	boldFormat string
+	bullet     string
+
+	ctx        context.Context
+	timeLayout string
 }
 
 func (o marshalOpts) withIncrementedIndentLevel() marshalOpts {
@@ -56,33 +120,147 @@ func (o marshalOpts) withIncrementedIndentLevel() marshalOpts {
 	return o
 }
 
-func marshal(v interface{}, opts marshalOpts) ([]byte, error) {
+// indent returns this level's line prefix: opts.prefix followed by
+// indentUnit repeated indentLevel times.
+func (o marshalOpts) indent() string {
+	return o.prefix + strings.Repeat(o.indentUnit, o.indentLevel)
+}
+
+// marshal writes the Markdown encoding of v into w, recursing into
+// structs, pointers, slices, arrays, and maps as needed.
+//
+// Type dispatch happens in priority order: MarshalerContext, then
+// Marshaler, then time.Time (checked ahead of TextMarshaler, since
+// time.Time implements it with a fixed layout we want to override),
+// then encoding.TextMarshaler, then fmt.Stringer, and only then the
+// reflect.Kind-based struct/slice/map/etc. handling below.
+func marshal(w *bufio.Writer, v interface{}, opts marshalOpts) error {
 	t := reflect.TypeOf(v)
 
+	if t.Implements(marshalerContextType) {
+		marshaled, err := v.(MarshalerContext).MarshalMarkdownContext(opts.ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(marshaled)
+
+		return err
+	}
+
 	if t.Implements(marshalerType) {
-		return v.(Marshaler).MarshalMarkdown()
+		marshaled, err := v.(Marshaler).MarshalMarkdown()
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(marshaled)
+
+		return err
+	}
+
+	if t == timeType {
+		return marshalTime(w, v.(time.Time), opts)
+	}
+
+	if t.Implements(textMarshalerType) {
+		marshaled, err := v.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(marshaled)
+
+		return err
+	}
+
+	if t.Implements(stringerType) {
+		_, err := w.WriteString(v.(fmt.Stringer).String())
+
+		return err
 	}
 
 	switch t.Kind() {
 	case reflect.String:
-		return marshalStr(v, opts)
+		return marshalStr(w, v, opts)
 	case reflect.Struct:
-		return marshalStruct(v, opts)
+		return marshalStruct(w, v, opts)
 	case reflect.Ptr:
-		return marshalPrt(v, opts)
+		return marshalPrt(w, v, opts)
+	case reflect.Slice, reflect.Array:
+		return marshalSlice(w, v, opts)
+	case reflect.Map:
+		return marshalMap(w, v, opts)
 	default:
-		return []byte(fmt.Sprintf("%v", v)), nil
+		_, err := fmt.Fprintf(w, "%v", v)
+
+		return err
 	}
 }
 
-func marshalStr(v interface{}, opts marshalOpts) ([]byte, error) {
+// tagOptions is the parsed form of a markdown struct tag.
+type tagOptions struct {
+	name      string
+	omit      bool
+	omitEmpty bool
+	obfuscate bool
+	code      bool
+	link      bool
+}
+
+// parseTag parses a markdown struct tag into its options. It accepts
+// the bare "-" and "obfuscate" values for backwards compatibility, in
+// addition to the comma-separated "name,option,option" form.
+func parseTag(rawTag string) tagOptions {
+	if rawTag == tagOmitField {
+		return tagOptions{omit: true}
+	}
+
+	if rawTag == tagObfuscateField {
+		return tagOptions{obfuscate: true}
+	}
+
+	parts := strings.Split(rawTag, ",")
+
+	opts := tagOptions{name: parts[0]}
+
+	for _, part := range parts[1:] {
+		switch part {
+		case tagOmitEmptyOption:
+			opts.omitEmpty = true
+		case tagObfuscateField:
+			opts.obfuscate = true
+		case tagCodeOption:
+			opts.code = true
+		case tagLinkOption:
+			opts.link = true
+		}
+	}
+
+	return opts
+}
+
+func marshalStr(w *bufio.Writer, v interface{}, opts marshalOpts) error {
 	valueStr := v.(string)
 
 	if opts.obfuscate {
 		valueStr = obfuscate(valueStr)
 	}
 
-	return []byte(valueStr), nil
+	_, err := w.WriteString(valueStr)
+
+	return err
+}
+
+func marshalTime(w *bufio.Writer, v time.Time, opts marshalOpts) error {
+	layout := opts.timeLayout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	_, err := w.WriteString(v.Format(layout))
+
+	return err
 }
 
 func obfuscate(str string) string {
@@ -95,22 +273,156 @@ func obfuscate(str string) string {
 	return strings.Repeat("*", length-4) + str[length-4:]
 }
 
-func marshalPrt(v interface{}, opts marshalOpts) ([]byte, error) {
+func marshalSlice(w *bufio.Writer, v interface{}, opts marshalOpts) error {
+	value := reflect.ValueOf(v)
+
+	if value.Kind() == reflect.Slice && value.IsNil() {
+		_, err := w.WriteString("null")
+
+		return err
+	}
+
+	if value.Len() == 0 {
+		return nil
+	}
+
+	innerOpts := opts.withIncrementedIndentLevel()
+	indent := opts.indent()
+
+	first := true
+	for i := 0; i < value.Len(); i++ {
+		elemValue := value.Index(i)
+		if !elemValue.CanInterface() {
+			continue
+		}
+
+		// Render the element before writing its bullet, so an
+		// element that marshals to nothing (an empty nested slice
+		// or map) skips the bullet line entirely instead of
+		// leaving a dangling "- " behind.
+		var buf bytes.Buffer
+		bw := bufio.NewWriter(&buf)
+
+		if err := marshal(bw, elemValue.Interface(), innerOpts); err != nil {
+			return err
+		}
+
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+
+		if buf.Len() == 0 {
+			continue
+		}
+
+		lineStart := "\n"
+		if first {
+			if opts.indentLevel == 0 {
+				lineStart = ""
+			}
+			first = false
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s%s ", lineStart, indent, opts.bullet); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func marshalMap(w *bufio.Writer, v interface{}, opts marshalOpts) error {
 	value := reflect.ValueOf(v)
 
 	if value.IsNil() {
-		return []byte("null"), nil
+		_, err := w.WriteString("null")
+
+		return err
+	}
+
+	if value.Len() == 0 {
+		return nil
+	}
+
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+	})
+
+	innerOpts := opts.withIncrementedIndentLevel()
+	indent := opts.indent()
+
+	first := true
+	for _, key := range keys {
+		elemValue := value.MapIndex(key)
+		if !elemValue.CanInterface() {
+			continue
+		}
+
+		// Render the value before writing its bullet, so a value
+		// that marshals to nothing (an empty nested slice or map)
+		// skips the bullet line entirely instead of leaving a
+		// dangling "- **key**: " behind.
+		var buf bytes.Buffer
+		bw := bufio.NewWriter(&buf)
+
+		if err := marshal(bw, elemValue.Interface(), innerOpts); err != nil {
+			return err
+		}
+
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+
+		if buf.Len() == 0 {
+			continue
+		}
+
+		lineStart := "\n"
+		if first {
+			if opts.indentLevel == 0 {
+				lineStart = ""
+			}
+			first = false
+		}
+
+		boldKey := fmt.Sprintf(opts.boldFormat, fmt.Sprintf("%v", key.Interface()))
+		if _, err := fmt.Fprintf(w, "%s%s%s %s: ", lineStart, indent, opts.bullet, boldKey); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
 	}
 
-	return marshal(value.Elem().Interface(), opts)
+	return nil
 }
 
-func marshalStruct(v interface{}, opts marshalOpts) ([]byte, error) {
-	lines := []string{}
+func marshalPrt(w *bufio.Writer, v interface{}, opts marshalOpts) error {
+	value := reflect.ValueOf(v)
+
+	if value.IsNil() {
+		_, err := w.WriteString("null")
+
+		return err
+	}
 
+	return marshal(w, value.Elem().Interface(), opts)
+}
+
+func marshalStruct(w *bufio.Writer, v interface{}, opts marshalOpts) error {
 	value := reflect.ValueOf(v)
 	t := reflect.TypeOf(v)
 
+	innerOptsBase := opts.withIncrementedIndentLevel()
+	indent := opts.indent()
+
+	first := true
 	for i := 0; i < value.NumField(); i++ {
 		fieldType := t.Field(i)
 		fieldValue := value.Field(i)
@@ -119,39 +431,116 @@ func marshalStruct(v interface{}, opts marshalOpts) ([]byte, error) {
 			continue
 		}
 
-		innerOpts := opts.withIncrementedIndentLevel()
-		if fieldType.Tag.Get(tag) == tagObfuscateField {
+		fieldTagOpts := parseTag(fieldType.Tag.Get(tag))
+		if fieldTagOpts.omit {
+			continue
+		}
+
+		if fieldTagOpts.omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+
+		innerOpts := innerOptsBase
+		if fieldTagOpts.obfuscate {
 			innerOpts.obfuscate = true
 		}
 
-		marshaledStructValue, err := marshal(fieldValue.Interface(), innerOpts)
-		if err != nil {
-			return nil, err
+		name := fieldType.Name
+		if fieldTagOpts.name != "" {
+			name = fieldTagOpts.name
 		}
 
-		if fieldType.Tag.Get(tag) == tagOmitField {
-			continue
+		lineStart := "\n"
+		if first {
+			if opts.indentLevel == 0 {
+				lineStart = ""
+			}
+			first = false
+		}
+
+		boldName := fmt.Sprintf(opts.boldFormat, name)
+		if _, err := fmt.Fprintf(w, "%s%s%s %s: ", lineStart, indent, opts.bullet, boldName); err != nil {
+			return err
 		}
 
-		line := fmt.Sprintf("- **%s**: %s", fieldType.Name, marshaledStructValue)
-		lines = append(lines, line)
+		if err := marshalTaggedField(w, fieldValue, fieldTagOpts, innerOpts); err != nil {
+			return err
+		}
 	}
 
-	lines = applyIndentation(lines, opts.indentLevel)
+	return nil
+}
+
+// marshalTaggedField renders a single struct field, honoring its
+// "link" and "code" tag options on top of the regular marshaling.
+func marshalTaggedField(w *bufio.Writer, fieldValue reflect.Value, fieldTagOpts tagOptions, opts marshalOpts) error {
+	if fieldTagOpts.link {
+		if linked, ok := marshalLink(fieldValue); ok {
+			return writeMaybeCode(w, []byte(linked), fieldTagOpts.code)
+		}
+	}
+
+	if !fieldTagOpts.code {
+		return marshal(w, fieldValue.Interface(), opts)
+	}
+
+	// Wrapping in backticks requires the fully rendered value up
+	// front, so the value is buffered here instead of streamed
+	// straight into w.
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+
+	if err := marshal(bw, fieldValue.Interface(), opts); err != nil {
+		return err
+	}
 
-	formattedLines := strings.Join(lines, "\n")
+	if err := bw.Flush(); err != nil {
+		return err
+	}
 
-	return []byte(formattedLines), nil
+	return writeMaybeCode(w, buf.Bytes(), true)
 }
 
-func applyIndentation(lines []string, level int) []string {
-	for i, line := range lines {
-		lines[i] = strings.Repeat("\t", level) + line
+// writeMaybeCode writes value into w, wrapping it in backticks (or a
+// fenced code block, if it spans multiple lines) when code is true.
+func writeMaybeCode(w *bufio.Writer, value []byte, code bool) error {
+	if !code {
+		_, err := w.Write(value)
+
+		return err
+	}
+
+	if bytes.Contains(value, []byte("\n")) {
+		_, err := fmt.Fprintf(w, "```\n%s\n```", value)
+
+		return err
 	}
 
-	if len(lines) > 0 && level > 0 {
-		lines[0] = "\n" + lines[0] // inner structs should start on a new line
+	_, err := fmt.Fprintf(w, "`%s`", value)
+
+	return err
+}
+
+// marshalLink renders fieldValue as a Slack link, "<url|label>", if
+// it is shaped like one: a [2]string{url, label}, or a struct with
+// URL and Text string fields. The second return value reports
+// whether fieldValue was link-shaped.
+func marshalLink(fieldValue reflect.Value) (string, bool) {
+	switch {
+	case fieldValue.Kind() == reflect.Array &&
+		fieldValue.Len() == 2 &&
+		fieldValue.Type().Elem().Kind() == reflect.String:
+		return fmt.Sprintf("<%s|%s>", fieldValue.Index(0).String(), fieldValue.Index(1).String()), true
+
+	case fieldValue.Kind() == reflect.Struct:
+		urlField := fieldValue.FieldByName("URL")
+		textField := fieldValue.FieldByName("Text")
+
+		if urlField.IsValid() && urlField.Kind() == reflect.String &&
+			textField.IsValid() && textField.Kind() == reflect.String {
+			return fmt.Sprintf("<%s|%s>", urlField.String(), textField.String()), true
+		}
 	}
 
-	return lines
+	return "", false
 }
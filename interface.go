@@ -1,7 +1,25 @@
 package md
 
+import "context"
+
 // Marshaler is the interface implemented by types that
 // can marshal themselves into custom Markdown language.
 type Marshaler interface {
 	MarshalMarkdown() ([]byte, error)
 }
+
+// MarshalerContext is the interface implemented by types that can
+// marshal themselves into custom Markdown language using a context,
+// for implementations that need per-request cancellation or logging
+// while rendering themselves (for example, when MarshalMarkdownContext
+// talks to a database or an API). It takes priority over Marshaler
+// when a type implements both.
+type MarshalerContext interface {
+	MarshalMarkdownContext(ctx context.Context) ([]byte, error)
+}
+
+// Unmarshaler is the interface implemented by types that can parse
+// the Markdown representation of themselves produced by Marshal.
+type Unmarshaler interface {
+	UnmarshalMarkdown([]byte) error
+}
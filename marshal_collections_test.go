@@ -0,0 +1,123 @@
+package md
+
+import "testing"
+
+func TestMarshalSliceNilVsEmpty(t *testing.T) {
+	var nilSlice []string
+
+	got, err := Marshal(nilSlice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "null" {
+		t.Errorf("nil slice: got %q, want %q", got, "null")
+	}
+
+	got, err = Marshal([]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "" {
+		t.Errorf("empty slice: got %q, want %q", got, "")
+	}
+}
+
+func TestMarshalMapNilVsEmpty(t *testing.T) {
+	var nilMap map[string]int
+
+	got, err := Marshal(nilMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "null" {
+		t.Errorf("nil map: got %q, want %q", got, "null")
+	}
+
+	got, err = Marshal(map[string]int{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "" {
+		t.Errorf("empty map: got %q, want %q", got, "")
+	}
+}
+
+func TestMarshalSlicePopulated(t *testing.T) {
+	got, err := Marshal([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- a\n- b"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalMapSortedByKey(t *testing.T) {
+	got, err := Marshal(map[string]int{"z": 1, "a": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **a**: 2\n- **z**: 1"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalNestedSliceInStruct(t *testing.T) {
+	type s struct {
+		Tags []string
+	}
+
+	got, err := Marshal(s{Tags: []string{"x", "y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Tags**: \n\t- x\n\t- y"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalSliceSkipsEmptyNestedElement(t *testing.T) {
+	got, err := Marshal([][]string{{"a"}, {}, {"b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- \n\t- a\n- \n\t- b"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalMapSkipsEmptyNestedValue(t *testing.T) {
+	got, err := Marshal(map[string][]string{"a": {"x"}, "b": {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **a**: \n\t- x"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalArray(t *testing.T) {
+	got, err := Marshal([2]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- 1\n- 2"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package md
+
+import "testing"
+
+func TestParseTagBackwardsCompat(t *testing.T) {
+	if got := parseTag("-"); !got.omit {
+		t.Errorf("parseTag(%q) = %+v, want omit", "-", got)
+	}
+
+	if got := parseTag("obfuscate"); !got.obfuscate || got.name != "" {
+		t.Errorf("parseTag(%q) = %+v, want bare obfuscate", "obfuscate", got)
+	}
+}
+
+func TestParseTagNameAndOptions(t *testing.T) {
+	cases := []struct {
+		tag  string
+		want tagOptions
+	}{
+		{"", tagOptions{}},
+		{"DisplayName", tagOptions{name: "DisplayName"}},
+		{"DisplayName,omitempty", tagOptions{name: "DisplayName", omitEmpty: true}},
+		{",obfuscate", tagOptions{obfuscate: true}},
+		{",code", tagOptions{code: true}},
+		{",link", tagOptions{link: true}},
+		{"DisplayName,omitempty,code", tagOptions{name: "DisplayName", omitEmpty: true, code: true}},
+		{",omitempty,code,link", tagOptions{omitEmpty: true, code: true, link: true}},
+	}
+
+	for _, c := range cases {
+		got := parseTag(c.tag)
+		if got != c.want {
+			t.Errorf("parseTag(%q) = %+v, want %+v", c.tag, got, c.want)
+		}
+	}
+}
+
+func TestMarshalOmitAndOmitEmpty(t *testing.T) {
+	type s struct {
+		Hidden string `markdown:"-"`
+		Empty  string `markdown:",omitempty"`
+		Kept   string
+	}
+
+	got, err := Marshal(s{Hidden: "secret", Empty: "", Kept: "value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Kept**: value"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalNameOverride(t *testing.T) {
+	type s struct {
+		Field string `markdown:"DisplayName"`
+	}
+
+	got, err := Marshal(s{Field: "value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **DisplayName**: value"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalCodeOption(t *testing.T) {
+	type s struct {
+		Inline string `markdown:",code"`
+		Multi  string `markdown:",code"`
+	}
+
+	got, err := Marshal(s{Inline: "x", Multi: "a\nb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Inline**: `x`\n- **Multi**: ```\na\nb\n```"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalLinkOption(t *testing.T) {
+	type link struct {
+		URL  string
+		Text string
+	}
+
+	type s struct {
+		Link [2]string `markdown:",link"`
+		Ref  link       `markdown:",link"`
+	}
+
+	got, err := Marshal(s{
+		Link: [2]string{"http://a", "A"},
+		Ref:  link{URL: "http://b", Text: "B"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Link**: <http://a|A>\n- **Ref**: <http://b|B>"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalObfuscateOption(t *testing.T) {
+	type s struct {
+		Secret string `markdown:",obfuscate"`
+	}
+
+	got, err := Marshal(s{Secret: "abcdefgh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- **Secret**: ****efgh"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}